@@ -0,0 +1,245 @@
+package gcloudvoice
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+
+	"github.com/pkg/errors"
+)
+
+// ChannelSplitter splits a stereo audio stream into its two channels. A
+// copy of the untouched input is written to orig as it is read, if orig is
+// non-nil.
+type ChannelSplitter interface {
+	Split(in io.Reader, orig, left, right io.Writer) error
+}
+
+// FFmpegSplitter splits a stereo input of any format ffmpeg understands by
+// shelling out to the `ffmpeg` binary.
+type FFmpegSplitter struct {
+	// BinaryPath overrides the `ffmpeg` binary used. Defaults to "ffmpeg",
+	// resolved via $PATH.
+	BinaryPath string
+}
+
+// Split implements ChannelSplitter.
+func (s FFmpegSplitter) Split(in io.Reader, orig, left, right io.Writer) error {
+	bin := s.BinaryPath
+	if bin == "" {
+		bin = "ffmpeg"
+	}
+
+	// ffmpeg's "pipe:N" output protocol writes to an inherited fd, so the
+	// right channel is handed to it as fd 3 via ExtraFiles rather than
+	// abusing the process's actual stdout/stderr, which would otherwise
+	// make it impossible to also capture ffmpeg's real error output.
+	rightPipeR, rightPipeW, err := os.Pipe()
+	if err != nil {
+		return errors.Wrap(err, "opening right channel pipe")
+	}
+	defer rightPipeR.Close()
+
+	cmd := exec.Command(bin,
+		"-y",
+		"-loglevel", "error",
+		// Input from stdin.
+		"-i", "pipe:0",
+		// Output to stdout.
+		"-f", "wav", "-map_channel", "0.0.0", "pipe:1",
+		// Output to fd 3.
+		"-f", "wav", "-map_channel", "0.0.1", "pipe:3",
+	)
+	cmd.ExtraFiles = []*os.File{rightPipeW}
+	cmd.Stdout = left
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return errors.Wrap(err, "opening stdin pipe")
+	}
+
+	if err := cmd.Start(); err != nil {
+		rightPipeW.Close()
+		return errors.Wrap(err, "starting command")
+	}
+	// The child now holds its own copy of the write end; the parent must
+	// close its copy or io.Copy below will never see EOF.
+	rightPipeW.Close()
+
+	copyRightErr := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(right, rightPipeR)
+		copyRightErr <- err
+	}()
+
+	var w io.Writer = stdin
+	if orig != nil {
+		w = io.MultiWriter(stdin, orig)
+	}
+	if _, err := io.Copy(w, in); err != nil {
+		return errors.Wrap(err, "copying")
+	}
+	stdin.Close()
+
+	if err := <-copyRightErr; err != nil {
+		return errors.Wrap(err, "copying right channel")
+	}
+
+	if err := cmd.Wait(); err != nil {
+		return errors.Wrapf(err, "running ffmpeg: %s", stderr.String())
+	}
+
+	return nil
+}
+
+// wavFormat holds the fields of a wav `fmt ` chunk needed to split and
+// re-encode its channels.
+type wavFormat struct {
+	NumChannels   uint16
+	SampleRate    uint32
+	BitsPerSample uint16
+	DataSize      uint32
+}
+
+// WAVSplitter splits a standard PCM wav input into two mono wav streams by
+// parsing the RIFF/`fmt `/`data` chunks directly, with no external process.
+// It only supports the common Twilio recording shape: two channels, 16 bits
+// per sample.
+type WAVSplitter struct{}
+
+// Split implements ChannelSplitter.
+func (WAVSplitter) Split(in io.Reader, orig, left, right io.Writer) error {
+	r := in
+	if orig != nil {
+		r = io.TeeReader(in, orig)
+	}
+
+	format, err := parseWAVHeader(r)
+	if err != nil {
+		return errors.Wrap(err, "parsing wav header")
+	}
+	if format.NumChannels != 2 {
+		return errors.Errorf("unsupported channel count: %d", format.NumChannels)
+	}
+	if format.BitsPerSample != 16 {
+		return errors.Errorf("unsupported bits per sample: %d", format.BitsPerSample)
+	}
+
+	if err := writeMonoWAVHeader(left, format); err != nil {
+		return errors.Wrap(err, "writing left wav header")
+	}
+	if err := writeMonoWAVHeader(right, format); err != nil {
+		return errors.Wrap(err, "writing right wav header")
+	}
+
+	// Bound the read to the data chunk's declared size: anything that
+	// follows it (a trailing LIST/id3 chunk, padding, etc.) is not audio
+	// and must not be read as a frame.
+	numFrames := format.DataSize / 4
+	frame := make([]byte, 4) // one L+R sample pair, 16 bits each
+	for i := uint32(0); i < numFrames; i++ {
+		if _, err := io.ReadFull(r, frame); err != nil {
+			return errors.Wrap(err, "reading pcm frame")
+		}
+		if _, err := left.Write(frame[0:2]); err != nil {
+			return errors.Wrap(err, "writing left channel")
+		}
+		if _, err := right.Write(frame[2:4]); err != nil {
+			return errors.Wrap(err, "writing right channel")
+		}
+	}
+
+	// Read whatever follows the data chunk through the tee so orig still
+	// receives the whole input.
+	if orig != nil {
+		if _, err := io.Copy(ioutil.Discard, r); err != nil {
+			return errors.Wrap(err, "copying remainder to orig")
+		}
+	}
+
+	return nil
+}
+
+// parseWAVHeader reads a RIFF/WAVE header from r, returning once the `data`
+// chunk header has been consumed so the caller can read raw PCM frames
+// directly from r afterwards.
+func parseWAVHeader(r io.Reader) (wavFormat, error) {
+	var riff [12]byte
+	if _, err := io.ReadFull(r, riff[:]); err != nil {
+		return wavFormat{}, errors.Wrap(err, "reading riff header")
+	}
+	if string(riff[0:4]) != "RIFF" || string(riff[8:12]) != "WAVE" {
+		return wavFormat{}, errors.New("not a wav file")
+	}
+
+	var format wavFormat
+	var haveFormat bool
+	for {
+		var chunkHdr [8]byte
+		if _, err := io.ReadFull(r, chunkHdr[:]); err != nil {
+			return wavFormat{}, errors.Wrap(err, "reading chunk header")
+		}
+		id := string(chunkHdr[0:4])
+		size := binary.LittleEndian.Uint32(chunkHdr[4:8])
+
+		switch id {
+		case "fmt ":
+			body := make([]byte, size)
+			if _, err := io.ReadFull(r, body); err != nil {
+				return wavFormat{}, errors.Wrap(err, "reading fmt chunk")
+			}
+			format.NumChannels = binary.LittleEndian.Uint16(body[2:4])
+			format.SampleRate = binary.LittleEndian.Uint32(body[4:8])
+			format.BitsPerSample = binary.LittleEndian.Uint16(body[14:16])
+			haveFormat = true
+		case "data":
+			if !haveFormat {
+				return wavFormat{}, errors.New("data chunk appeared before fmt chunk")
+			}
+			format.DataSize = size
+			return format, nil
+		default:
+			if _, err := io.CopyN(ioutil.Discard, r, int64(size)); err != nil {
+				return wavFormat{}, errors.Wrapf(err, "skipping %q chunk", id)
+			}
+		}
+		if size%2 == 1 {
+			// Chunks are word-aligned; skip the pad byte.
+			if _, err := io.CopyN(ioutil.Discard, r, 1); err != nil {
+				return wavFormat{}, errors.Wrap(err, "skipping chunk pad byte")
+			}
+		}
+	}
+}
+
+// writeMonoWAVHeader writes a well-formed 44-byte canonical wav header for
+// a single 16-bit mono channel derived from a stereo format.
+func writeMonoWAVHeader(w io.Writer, format wavFormat) error {
+	const blockAlign = 2 // 16-bit mono
+	byteRate := format.SampleRate * blockAlign
+	dataSize := format.DataSize / uint32(format.NumChannels)
+
+	hdr := make([]byte, 44)
+	copy(hdr[0:4], "RIFF")
+	binary.LittleEndian.PutUint32(hdr[4:8], 36+dataSize)
+	copy(hdr[8:12], "WAVE")
+	copy(hdr[12:16], "fmt ")
+	binary.LittleEndian.PutUint32(hdr[16:20], 16) // fmt chunk size
+	binary.LittleEndian.PutUint16(hdr[20:22], 1)  // PCM
+	binary.LittleEndian.PutUint16(hdr[22:24], 1)  // mono
+	binary.LittleEndian.PutUint32(hdr[24:28], format.SampleRate)
+	binary.LittleEndian.PutUint32(hdr[28:32], byteRate)
+	binary.LittleEndian.PutUint16(hdr[32:34], blockAlign)
+	binary.LittleEndian.PutUint16(hdr[34:36], 16) // bits per sample
+	copy(hdr[36:40], "data")
+	binary.LittleEndian.PutUint32(hdr[40:44], dataSize)
+
+	_, err := w.Write(hdr)
+	return err
+}
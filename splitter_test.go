@@ -0,0 +1,74 @@
+package gcloudvoice_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/upgear/gcloudvoice"
+)
+
+// stereoWAV builds a minimal 16-bit stereo PCM wav file from interleaved
+// samples.
+func stereoWAV(sampleRate uint32, samples [][2]int16) []byte {
+	dataSize := uint32(len(samples) * 4)
+
+	buf := new(bytes.Buffer)
+	buf.WriteString("RIFF")
+	binary.Write(buf, binary.LittleEndian, uint32(36+dataSize))
+	buf.WriteString("WAVE")
+	buf.WriteString("fmt ")
+	binary.Write(buf, binary.LittleEndian, uint32(16))
+	binary.Write(buf, binary.LittleEndian, uint16(1)) // PCM
+	binary.Write(buf, binary.LittleEndian, uint16(2)) // stereo
+	binary.Write(buf, binary.LittleEndian, sampleRate)
+	binary.Write(buf, binary.LittleEndian, sampleRate*4) // byte rate
+	binary.Write(buf, binary.LittleEndian, uint16(4))    // block align
+	binary.Write(buf, binary.LittleEndian, uint16(16))   // bits per sample
+	buf.WriteString("data")
+	binary.Write(buf, binary.LittleEndian, dataSize)
+	for _, s := range samples {
+		binary.Write(buf, binary.LittleEndian, s[0])
+		binary.Write(buf, binary.LittleEndian, s[1])
+	}
+
+	return buf.Bytes()
+}
+
+func TestWAVSplitterSplit(t *testing.T) {
+	samples := [][2]int16{{1, -1}, {2, -2}, {3, -3}}
+	wav := stereoWAV(8000, samples)
+
+	var orig, left, right bytes.Buffer
+	if err := (gcloudvoice.WAVSplitter{}).Split(bytes.NewReader(wav), &orig, &left, &right); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(orig.Bytes(), wav) {
+		t.Error("orig was not a faithful copy of the input")
+	}
+
+	checkMono := func(name string, buf bytes.Buffer, want []int16) {
+		t.Helper()
+		if buf.Len() != 44+len(want)*2 {
+			t.Fatalf("%s: got %d bytes, want %d", name, buf.Len(), 44+len(want)*2)
+		}
+		b := buf.Bytes()
+		if string(b[0:4]) != "RIFF" || string(b[8:12]) != "WAVE" {
+			t.Fatalf("%s: missing RIFF/WAVE header", name)
+		}
+		numChannels := binary.LittleEndian.Uint16(b[22:24])
+		if numChannels != 1 {
+			t.Fatalf("%s: got %d channels, want 1", name, numChannels)
+		}
+		for i, wantSample := range want {
+			got := int16(binary.LittleEndian.Uint16(b[44+i*2 : 46+i*2]))
+			if got != wantSample {
+				t.Errorf("%s: sample %d = %d, want %d", name, i, got, wantSample)
+			}
+		}
+	}
+
+	checkMono("left", left, []int16{1, 2, 3})
+	checkMono("right", right, []int16{-1, -2, -3})
+}
@@ -8,12 +8,12 @@ fill those gaps.
 package gcloudvoice
 
 import (
+	"bufio"
 	"context"
 	"fmt"
 	"io"
 	"math/rand"
 	"net/http"
-	"os/exec"
 	"path"
 	"strings"
 	"time"
@@ -43,9 +43,39 @@ type Message struct {
 	// Channel is 0 or 1 indicating left or right channel.
 	// This would be used to identify the caller/called speaker in a phone
 	// conversation.
-	Channel bool
-	Offset  time.Duration
-	Text    string
+	Channel    bool
+	Offset     time.Duration
+	EndOffset  time.Duration
+	Text       string
+	Confidence float32
+	// Words holds per-word timing and confidence. It is populated by the
+	// batch transcription methods; TranscribeStream leaves it empty.
+	Words []Word
+	// LanguageCode is the BCP-47 language code Speech recognized the
+	// message in, e.g. "en-US".
+	LanguageCode string
+
+	// IsFinal indicates that Speech considers this result final. It is
+	// only ever false for interim results returned by TranscribeStream.
+	IsFinal bool
+	// Stability estimates how likely the transcript is to change as more
+	// audio is processed, from 0 (unstable) to 1 (stable). It is only
+	// populated for interim (IsFinal == false) results from
+	// TranscribeStream.
+	Stability float32
+}
+
+// Word is a single transcribed word with its timing, confidence, and
+// (when speaker diarization is enabled) the speaker it was attributed to.
+type Word struct {
+	Text       string
+	Start      time.Duration
+	End        time.Duration
+	Confidence float32
+	// SpeakerTag identifies the speaker this word was attributed to. It is
+	// only populated when Client.EnableSpeakerDiarization is set, and is 0
+	// otherwise.
+	SpeakerTag int32
 }
 
 // ByTime is a type that conforms to the `sort` package for sorting
@@ -56,6 +86,39 @@ func (s ByTime) Len() int           { return len(s) }
 func (s ByTime) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
 func (s ByTime) Less(i, j int) bool { return s[i].Offset < s[j].Offset }
 
+// AudioFormat identifies the wire format of audio passed to
+// Client.TranscribeReader.
+type AudioFormat int
+
+const (
+	// FormatWAVLinear16 is uncompressed, linear PCM audio in a wav
+	// container. This is the format TranscribeURL and TranscribeURLNative
+	// expect.
+	FormatWAVLinear16 AudioFormat = iota
+	// FormatMULAW is 8-bit mu-law encoded audio, Twilio's default
+	// `<Record>` format.
+	FormatMULAW
+	// FormatFLAC is FLAC compressed audio.
+	FormatFLAC
+	// FormatOGGOpus is Opus audio in an Ogg container.
+	FormatOGGOpus
+)
+
+// encoding translates an AudioFormat into the corresponding Speech
+// RecognitionConfig encoding.
+func (f AudioFormat) encoding() speechpb.RecognitionConfig_AudioEncoding {
+	switch f {
+	case FormatMULAW:
+		return speechpb.RecognitionConfig_MULAW
+	case FormatFLAC:
+		return speechpb.RecognitionConfig_FLAC
+	case FormatOGGOpus:
+		return speechpb.RecognitionConfig_OGG_OPUS
+	default:
+		return speechpb.RecognitionConfig_LINEAR16
+	}
+}
+
 // Client wraps google `storage` and `speech` clients.
 type Client struct {
 	// Required: Google storage bucket to use
@@ -77,12 +140,26 @@ type Client struct {
 	// Phrases to seed the speech recognition with
 	Phrases         []string
 	ProfanityFilter bool
+
+	// Set to true to attribute words within a single channel to distinct
+	// speakers (e.g. for conference-call recordings where the caller and
+	// callee share a channel). DiarizationSpeakerCount should be set to
+	// the expected number of speakers when known; Speech attempts to
+	// detect it otherwise.
+	EnableSpeakerDiarization bool
+	DiarizationSpeakerCount  int
+
+	// Splitter controls how TranscribeURL splits stereo input into its two
+	// channels. Defaults to WAVSplitter for wav (RIFF/WAVE) input,
+	// falling back to FFmpegSplitter for anything else.
+	Splitter ChannelSplitter
 }
 
 // TranscribeURL grabs a stereo `wav` file from an http url. It splits the
-// channels using a system call to `ffmpeg` and transcribes the messages and
-// combines them into a single slice of messages. It does not sort them by
-// time, so a subsequent call to `sort.Sort(gcloudvoice.ByTime(msgs))` is
+// channels using Client.Splitter (defaulting to WAVSplitter for wav
+// (RIFF/WAVE) input, or FFmpegSplitter otherwise) and transcribes the messages
+// and combines them into a single slice of messages. It does not sort them
+// by time, so a subsequent call to `sort.Sort(gcloudvoice.ByTime(msgs))` is
 // needed for most use cases.
 func (c *Client) TranscribeURL(ctx context.Context, url, name string) (msgs []Message, rerr error) {
 	resp, err := http.Get(url)
@@ -138,7 +215,20 @@ func (c *Client) TranscribeURL(ctx context.Context, url, name string) (msgs []Me
 
 	leftW := leftObj.NewWriter(ctx)
 	rightW := rightObj.NewWriter(ctx)
-	if err := splitWavChannels(resp.Body, origW, leftW, rightW); err != nil {
+
+	in := io.Reader(resp.Body)
+	splitter := c.Splitter
+	if splitter == nil {
+		br := bufio.NewReaderSize(resp.Body, 12)
+		peek, _ := br.Peek(12)
+		if len(peek) == 12 && string(peek[0:4]) == "RIFF" && string(peek[8:12]) == "WAVE" {
+			splitter = WAVSplitter{}
+		} else {
+			splitter = FFmpegSplitter{}
+		}
+		in = br
+	}
+	if err := splitter.Split(in, origW, leftW, rightW); err != nil {
 		return nil, errors.Wrap(err, "splitting wav")
 	}
 
@@ -158,7 +248,7 @@ func (c *Client) TranscribeURL(ctx context.Context, url, name string) (msgs []Me
 	leftMsgs, rightMsgs := make(chan []Message), make(chan []Message)
 	var transcribeGrp errgroup.Group
 	transcribeGrp.Go(func() error {
-		msgs, err := transcribeChannel(ctx, c.Speech, gsPath(leftName), true, c.Phrases, c.ProfanityFilter)
+		msgs, err := transcribeChannel(ctx, c.Speech, gsPath(leftName), true, c.Phrases, c.ProfanityFilter, speechpb.RecognitionConfig_LINEAR16, 8000, c.EnableSpeakerDiarization, c.DiarizationSpeakerCount)
 		if err != nil {
 			leftMsgs <- nil
 			return errors.Wrap(err, "left channel")
@@ -167,7 +257,7 @@ func (c *Client) TranscribeURL(ctx context.Context, url, name string) (msgs []Me
 		return nil
 	})
 	transcribeGrp.Go(func() error {
-		msgs, err := transcribeChannel(ctx, c.Speech, gsPath(rightName), false, c.Phrases, c.ProfanityFilter)
+		msgs, err := transcribeChannel(ctx, c.Speech, gsPath(rightName), false, c.Phrases, c.ProfanityFilter, speechpb.RecognitionConfig_LINEAR16, 8000, c.EnableSpeakerDiarization, c.DiarizationSpeakerCount)
 		if err != nil {
 			rightMsgs <- nil
 			return errors.Wrap(err, "right channel")
@@ -179,64 +269,322 @@ func (c *Client) TranscribeURL(ctx context.Context, url, name string) (msgs []Me
 	return append(<-leftMsgs, <-rightMsgs...), errors.Wrap(transcribeGrp.Wait(), "transcribing")
 }
 
-// splitWavChannels splits a stereo `wav` format input into its two channels.
-// It assumes `ffmpeg` is installed an in the $PATH.
-func splitWavChannels(in io.Reader, orig, left, right io.Writer) error {
-	// If this fails the error msg will be lost b/c we are abusing
-	// stderr. However, the code to incorporate named pipes is not
-	// worth the increased complexity IMO.
-	cmd := exec.Command("ffmpeg",
-		"-y",
-		"-loglevel", "panic",
-		// Input from stdin.
-		"-i", "pipe:0",
-		// Output to stdout.
-		"-f", "wav", "-map_channel", "0.0.0", "pipe:1",
-		// Output to stderr.
-		"-f", "wav", "-map_channel", "0.0.1", "pipe:2",
-	)
-
-	// Map pipes.
-	stdin, err := cmd.StdinPipe()
+// TranscribeURLNative grabs a stereo `wav` file from an http url and
+// transcribes both channels in a single `LongRunningRecognize` call using
+// Speech's native multi-channel support (`AudioChannelCount` +
+// `EnableSeparateRecognitionPerChannel`), rather than splitting the audio
+// into two files with Client.Splitter and issuing two recognize calls like
+// TranscribeURL. This halves the number of google storage objects and the
+// Speech API cost for callers who don't need the split intermediate files.
+// Speech does not support diarization alongside multi-channel recognition,
+// so c.EnableSpeakerDiarization is ignored here.
+func (c *Client) TranscribeURLNative(ctx context.Context, url, name string) (msgs []Message, rerr error) {
+	resp, err := http.Get(url)
 	if err != nil {
-		return errors.Wrap(err, "opening stdin pipe")
+		return nil, errors.Wrap(err, "unable to GET url")
+	}
+	defer resp.Body.Close()
+
+	if name == "" {
+		name = path.Base(url)
+	}
+	name = strings.TrimSuffix(name, ".wav")
+	origName := name + ".wav"
+
+	bkt := c.Storage.Bucket(c.StorageBucket)
+	origObj := bkt.Object(origName)
+
+	if c.MakeOriginalPublic && c.StoreOriginal {
+		defer func() {
+			err := origObj.ACL().Set(ctx, storage.AllUsers, storage.RoleReader)
+			if err != nil {
+				rerr = multierror.Append(rerr, errors.Wrapf(ErrMakingPublic, "original file: %s", err))
+			}
+		}()
+	}
+	if !c.StoreOriginal && !c.KeepIntermediateFiles {
+		// Cleanup gcloud storage object.
+		defer func() {
+			if err := origObj.Delete(ctx); err != nil && err != storage.ErrObjectNotExist {
+				rerr = multierror.Append(rerr, errors.Wrapf(ErrDeleting, "original file: %s", err))
+			}
+		}()
+	}
+
+	origObjW := origObj.NewWriter(ctx)
+	if _, err := io.Copy(origObjW, resp.Body); err != nil {
+		origObjW.Close()
+		return nil, errors.Wrap(err, "copying to gcloud storage")
+	}
+	if err := origObjW.Close(); err != nil {
+		return nil, errors.Wrap(err, "closing gcloud storage writer")
+	}
+
+	uri := fmt.Sprintf("gs://%s/%s", c.StorageBucket, origName)
+
+	msgs, err = transcribeChannelsNative(ctx, c.Speech, uri, c.Phrases, c.ProfanityFilter, speechpb.RecognitionConfig_LINEAR16, 8000, 2)
+	return msgs, errors.Wrap(err, "transcribing")
+}
+
+// TranscribeReader uploads r to google storage as name and transcribes it
+// using the given format, sample rate, and channel count. Unlike
+// TranscribeURL, it never shells out to ffmpeg: Twilio's native recording
+// formats (FormatMULAW, FormatFLAC) are sent to Speech as-is, and when
+// channels is greater than one, Speech's native multi-channel support
+// (see TranscribeURLNative) is used to split them instead of a
+// pre-processing step.
+func (c *Client) TranscribeReader(ctx context.Context, r io.Reader, format AudioFormat, sampleRate, channels int, name string) (msgs []Message, rerr error) {
+	if name == "" {
+		name = fmt.Sprintf("%d", rnd.Int63())
+	}
+
+	bkt := c.Storage.Bucket(c.StorageBucket)
+	obj := bkt.Object(name)
+
+	if c.MakeOriginalPublic && c.StoreOriginal {
+		defer func() {
+			err := obj.ACL().Set(ctx, storage.AllUsers, storage.RoleReader)
+			if err != nil {
+				rerr = multierror.Append(rerr, errors.Wrapf(ErrMakingPublic, "file: %s", err))
+			}
+		}()
+	}
+	if !c.StoreOriginal && !c.KeepIntermediateFiles {
+		// Cleanup gcloud storage object.
+		defer func() {
+			if err := obj.Delete(ctx); err != nil && err != storage.ErrObjectNotExist {
+				rerr = multierror.Append(rerr, errors.Wrapf(ErrDeleting, "file: %s", err))
+			}
+		}()
+	}
+
+	objW := obj.NewWriter(ctx)
+	if _, err := io.Copy(objW, r); err != nil {
+		objW.Close()
+		return nil, errors.Wrap(err, "copying to gcloud storage")
+	}
+	if err := objW.Close(); err != nil {
+		return nil, errors.Wrap(err, "closing gcloud storage writer")
+	}
+
+	uri := fmt.Sprintf("gs://%s/%s", c.StorageBucket, name)
+
+	if channels > 1 {
+		// Diarization is not supported alongside native multi-channel
+		// recognition; c.EnableSpeakerDiarization is ignored here.
+		msgs, err := transcribeChannelsNative(ctx, c.Speech, uri, c.Phrases, c.ProfanityFilter, format.encoding(), int32(sampleRate), int32(channels))
+		return msgs, errors.Wrap(err, "transcribing")
+	}
+	msgs, err := transcribeChannel(ctx, c.Speech, uri, false, c.Phrases, c.ProfanityFilter, format.encoding(), int32(sampleRate), c.EnableSpeakerDiarization, c.DiarizationSpeakerCount)
+	return msgs, errors.Wrap(err, "transcribing")
+}
+
+// TranscribeStream transcribes a live, stereo PCM audio stream (e.g. frames
+// forwarded from a Twilio `<Stream>` webhook) using Google Speech's
+// streaming recognition API. Unlike TranscribeURL, there is no round-trip
+// through google storage: the interleaved stereo samples are de-interleaved
+// on the fly and streamed directly to two concurrent `StreamingRecognize`
+// calls, one per channel. Results are forwarded on the returned channel as
+// they arrive, including interim (IsFinal == false) results.
+//
+// The returned error channel receives at most one error. Both channels are
+// closed once transcription of the stream has finished.
+func (c *Client) TranscribeStream(ctx context.Context, r io.Reader, name string) (<-chan Message, <-chan error) {
+	msgs := make(chan Message)
+	errs := make(chan error, 1)
+
+	leftR, leftW := io.Pipe()
+	rightR, rightW := io.Pipe()
+
+	go func() {
+		if err := demuxPCMChannels(r, leftW, rightW); err != nil {
+			leftW.CloseWithError(err)
+			rightW.CloseWithError(err)
+			return
+		}
+		leftW.Close()
+		rightW.Close()
+	}()
+
+	go func() {
+		defer close(msgs)
+		defer close(errs)
+
+		var streamGrp errgroup.Group
+		streamGrp.Go(func() error {
+			return streamChannel(ctx, c.Speech, leftR, true, c.Phrases, c.ProfanityFilter, msgs)
+		})
+		streamGrp.Go(func() error {
+			return streamChannel(ctx, c.Speech, rightR, false, c.Phrases, c.ProfanityFilter, msgs)
+		})
+		if err := streamGrp.Wait(); err != nil {
+			errs <- errors.Wrap(err, "streaming")
+		}
+	}()
+
+	return msgs, errs
+}
+
+// demuxPCMChannels reads interleaved 16-bit stereo PCM samples from r and
+// writes the de-interleaved left/right mono samples to left and right as
+// they arrive. It is a pure-Go stand-in for `ffmpeg -map_channel` that
+// avoids forking a process on the hot path of a live audio stream.
+func demuxPCMChannels(r io.Reader, left, right io.Writer) error {
+	frame := make([]byte, 4) // one L+R sample pair, 16 bits each
+	for {
+		if _, err := io.ReadFull(r, frame); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return nil
+			}
+			return errors.Wrap(err, "reading pcm frame")
+		}
+		if _, err := left.Write(frame[0:2]); err != nil {
+			return errors.Wrap(err, "writing left channel")
+		}
+		if _, err := right.Write(frame[2:4]); err != nil {
+			return errors.Wrap(err, "writing right channel")
+		}
 	}
-	cmd.Stderr = left
-	cmd.Stdout = right
+}
 
-	if err := cmd.Start(); err != nil {
-		return errors.Wrapf(err, "starting command")
+// streamChannel opens a `StreamingRecognize` call for a single de-interleaved
+// PCM channel, sending the initial `StreamingRecognitionConfig` followed by
+// raw audio chunks read from r, and forwards results onto out as they
+// arrive.
+func streamChannel(ctx context.Context, c *speech.Client, r io.Reader, chn bool, phrases []string, profanityFilter bool, out chan<- Message) error {
+	stream, err := c.StreamingRecognize(ctx)
+	if err != nil {
+		return errors.Wrap(err, "opening streaming recognize")
 	}
 
-	var w io.Writer
-	if orig != nil {
-		w = io.MultiWriter(stdin, orig)
-	} else {
-		w = stdin
+	cfg := &speechpb.RecognitionConfig{
+		Encoding:              speechpb.RecognitionConfig_LINEAR16,
+		SampleRateHertz:       8000,
+		LanguageCode:          "en-US",
+		EnableWordTimeOffsets: true,
+		ProfanityFilter:       profanityFilter,
+	}
+	if len(phrases) > 0 {
+		cfg.SpeechContexts = []*speechpb.SpeechContext{{Phrases: phrases}}
+	}
+	req := &speechpb.StreamingRecognizeRequest{
+		StreamingRequest: &speechpb.StreamingRecognizeRequest_StreamingConfig{
+			StreamingConfig: &speechpb.StreamingRecognitionConfig{
+				Config:          cfg,
+				SingleUtterance: false,
+				InterimResults:  true,
+			},
+		},
+	}
+	if err := stream.Send(req); err != nil {
+		return errors.Wrap(err, "sending streaming config")
 	}
-	if _, err := io.Copy(w, in); err != nil {
-		return errors.Wrap(err, "copying")
+
+	var sendGrp errgroup.Group
+	sendGrp.Go(func() error {
+		buf := make([]byte, 1024)
+		for {
+			n, err := r.Read(buf)
+			if n > 0 {
+				sendErr := stream.Send(&speechpb.StreamingRecognizeRequest{
+					StreamingRequest: &speechpb.StreamingRecognizeRequest_AudioContent{
+						AudioContent: append([]byte(nil), buf[:n]...),
+					},
+				})
+				if sendErr != nil {
+					return errors.Wrap(sendErr, "sending audio chunk")
+				}
+			}
+			if err != nil {
+				if err == io.EOF {
+					return errors.Wrap(stream.CloseSend(), "closing send stream")
+				}
+				return errors.Wrap(err, "reading audio chunk")
+			}
+		}
+	})
+
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return errors.Wrap(err, "receiving streaming response")
+		}
+		if resp.Error != nil {
+			return errors.Errorf("streaming recognize: %s", resp.Error.GetMessage())
+		}
+		for _, result := range resp.Results {
+			if len(result.Alternatives) == 0 {
+				continue
+			}
+			out <- Message{
+				Channel:   chn,
+				Text:      result.Alternatives[0].Transcript,
+				IsFinal:   result.IsFinal,
+				Stability: result.Stability,
+			}
+		}
 	}
-	stdin.Close()
 
-	if err := cmd.Wait(); err != nil {
-		return errors.Wrapf(err, "waiting for command to finish")
+	return errors.Wrap(sendGrp.Wait(), "sending audio")
+}
+
+// wordsFromAlternative converts an alternative's word-level results into the
+// package's own Word type.
+func wordsFromAlternative(alt *speechpb.SpeechRecognitionAlternative) ([]Word, error) {
+	words := make([]Word, len(alt.Words))
+	for i, w := range alt.Words {
+		start, err := ptypes.Duration(w.StartTime)
+		if err != nil {
+			return nil, errors.Wrap(err, "converting word start time")
+		}
+		end, err := ptypes.Duration(w.EndTime)
+		if err != nil {
+			return nil, errors.Wrap(err, "converting word end time")
+		}
+		words[i] = Word{
+			Text:       w.Word,
+			Start:      start,
+			End:        end,
+			Confidence: w.Confidence,
+			SpeakerTag: w.SpeakerTag,
+		}
 	}
+	return words, nil
+}
 
-	return nil
+// diarizationConfig builds a SpeakerDiarizationConfig from a Client's
+// diarization options, or nil when diarization is disabled.
+func diarizationConfig(enable bool, speakerCount int) *speechpb.SpeakerDiarizationConfig {
+	if !enable {
+		return nil
+	}
+	cfg := &speechpb.SpeakerDiarizationConfig{EnableSpeakerDiarization: true}
+	if speakerCount > 0 {
+		cfg.MinSpeakerCount = int32(speakerCount)
+		cfg.MaxSpeakerCount = int32(speakerCount)
+	}
+	return cfg
 }
 
 // transcribeChannel reaches out to google's speech to text api and transcribes
 // a single wav channel.
-func transcribeChannel(ctx context.Context, c *speech.Client, uri string, chn bool, phrases []string, profanityFilter bool) ([]Message, error) {
+func transcribeChannel(ctx context.Context, c *speech.Client, uri string, chn bool, phrases []string, profanityFilter bool, encoding speechpb.RecognitionConfig_AudioEncoding, sampleRateHertz int32, enableDiarization bool, diarizationSpeakerCount int) ([]Message, error) {
+	cfg := &speechpb.RecognitionConfig{
+		Encoding:              encoding,
+		SampleRateHertz:       sampleRateHertz,
+		LanguageCode:          "en-US",
+		EnableWordTimeOffsets: true,
+		ProfanityFilter:       profanityFilter,
+		DiarizationConfig:     diarizationConfig(enableDiarization, diarizationSpeakerCount),
+	}
+	if len(phrases) > 0 {
+		cfg.SpeechContexts = []*speechpb.SpeechContext{{Phrases: phrases}}
+	}
 	op, err := c.LongRunningRecognize(ctx, &speechpb.LongRunningRecognizeRequest{
-		Config: &speechpb.RecognitionConfig{
-			Encoding:              speechpb.RecognitionConfig_LINEAR16,
-			SampleRateHertz:       8000,
-			LanguageCode:          "en-US",
-			EnableWordTimeOffsets: true,
-			ProfanityFilter:       profanityFilter,
-		},
+		Config: cfg,
 		Audio: &speechpb.RecognitionAudio{
 			AudioSource: &speechpb.RecognitionAudio_Uri{Uri: uri},
 		},
@@ -257,17 +605,87 @@ func transcribeChannel(ctx context.Context, c *speech.Client, uri string, chn bo
 			continue
 		}
 		alt0 := result.Alternatives[0]
-		word0 := alt0.Words[0]
 
-		dur, err := ptypes.Duration(word0.StartTime)
+		words, err := wordsFromAlternative(alt0)
+		if err != nil {
+			return nil, err
+		}
+
+		msgs = append(msgs, Message{
+			Channel:      chn,
+			Offset:       words[0].Start,
+			EndOffset:    words[len(words)-1].End,
+			Text:         alt0.Transcript,
+			Confidence:   alt0.Confidence,
+			Words:        words,
+			LanguageCode: cfg.LanguageCode,
+			IsFinal:      true,
+		})
+	}
+
+	return msgs, nil
+}
+
+// transcribeChannelsNative reaches out to google's speech to text api and
+// transcribes both channels of a stereo recording in a single
+// `LongRunningRecognize` call, using `AudioChannelCount` and
+// `EnableSeparateRecognitionPerChannel` to have Speech split the channels
+// itself rather than recognizing two pre-split mono files.
+func transcribeChannelsNative(ctx context.Context, c *speech.Client, uri string, phrases []string, profanityFilter bool, encoding speechpb.RecognitionConfig_AudioEncoding, sampleRateHertz int32, channelCount int32) ([]Message, error) {
+	// Speech rejects DiarizationConfig combined with
+	// EnableSeparateRecognitionPerChannel, so diarization is not
+	// supported on this multi-channel path.
+	cfg := &speechpb.RecognitionConfig{
+		Encoding:                            encoding,
+		SampleRateHertz:                     sampleRateHertz,
+		LanguageCode:                        "en-US",
+		EnableWordTimeOffsets:               true,
+		ProfanityFilter:                     profanityFilter,
+		AudioChannelCount:                   channelCount,
+		EnableSeparateRecognitionPerChannel: true,
+	}
+	if len(phrases) > 0 {
+		cfg.SpeechContexts = []*speechpb.SpeechContext{{Phrases: phrases}}
+	}
+	op, err := c.LongRunningRecognize(ctx, &speechpb.LongRunningRecognizeRequest{
+		Config: cfg,
+		Audio: &speechpb.RecognitionAudio{
+			AudioSource: &speechpb.RecognitionAudio_Uri{Uri: uri},
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "starting longrunning recognize")
+	}
+
+	resp, err := op.Wait(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "waiting on longrunning recognize")
+	}
+
+	// Parse the results. ChannelTag is 1-indexed (1 == left, 2 == right),
+	// matching TranscribeURL's split path, which assigns Channel=true to
+	// the left channel.
+	var msgs []Message
+	for _, result := range resp.Results {
+		if len(result.Alternatives) == 0 || len(result.Alternatives[0].Words) == 0 {
+			continue
+		}
+		alt0 := result.Alternatives[0]
+
+		words, err := wordsFromAlternative(alt0)
 		if err != nil {
-			return nil, errors.Wrap(err, "converting word duration")
+			return nil, err
 		}
 
 		msgs = append(msgs, Message{
-			Channel: chn,
-			Offset:  dur,
-			Text:    alt0.Transcript,
+			Channel:      result.ChannelTag == 1,
+			Offset:       words[0].Start,
+			EndOffset:    words[len(words)-1].End,
+			Text:         alt0.Transcript,
+			Confidence:   alt0.Confidence,
+			Words:        words,
+			LanguageCode: cfg.LanguageCode,
+			IsFinal:      true,
 		})
 	}
 
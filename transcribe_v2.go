@@ -0,0 +1,180 @@
+package gcloudvoice
+
+import (
+	"context"
+	"fmt"
+
+	speechv2 "cloud.google.com/go/speech/apiv2"
+	"github.com/pkg/errors"
+	speechpbv2 "google.golang.org/genproto/googleapis/cloud/speech/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ClientV2 wraps Google's Speech-to-Text v2 API. Unlike Client, which talks
+// to the global v1 API, ClientV2 is scoped to a project/location and speaks
+// to that location's endpoint (e.g. `eu-speech.googleapis.com:443`), which
+// is required for callers with data-residency requirements. Build Speech
+// with `option.WithEndpoint(Location + "-speech.googleapis.com:443")` (or
+// the bare host for "global") before constructing a ClientV2.
+type ClientV2 struct {
+	// Required: Google speech v2 client, pointed at the endpoint matching
+	// Location.
+	Speech *speechv2.Client
+
+	// Required: GCP project ID.
+	ProjectID string
+	// Required: Speech v2 location, e.g. "us-central1", "europe-west4", or
+	// "global".
+	Location string
+	// Required: Recognizer ID to use under ProjectID/Location. It is
+	// created automatically, using Model and LanguageCodes, if it does
+	// not already exist.
+	RecognizerID string
+
+	// Model to recognize with when auto-creating the recognizer, e.g.
+	// "long" or "telephony".
+	Model string
+	// LanguageCodes to recognize with when auto-creating the recognizer.
+	LanguageCodes []string
+}
+
+// parent returns the project/location resource name ClientV2 operates
+// under.
+func (c *ClientV2) parent() string {
+	return fmt.Sprintf("projects/%s/locations/%s", c.ProjectID, c.Location)
+}
+
+// recognizerName returns the full resource name of the configured
+// recognizer.
+func (c *ClientV2) recognizerName() string {
+	return fmt.Sprintf("%s/recognizers/%s", c.parent(), c.RecognizerID)
+}
+
+// ensureRecognizer creates the configured recognizer if it does not already
+// exist.
+func (c *ClientV2) ensureRecognizer(ctx context.Context) error {
+	_, err := c.Speech.GetRecognizer(ctx, &speechpbv2.GetRecognizerRequest{Name: c.recognizerName()})
+	if err == nil {
+		return nil
+	}
+	if status.Code(err) != codes.NotFound {
+		return errors.Wrap(err, "getting recognizer")
+	}
+
+	op, err := c.Speech.CreateRecognizer(ctx, &speechpbv2.CreateRecognizerRequest{
+		Parent:       c.parent(),
+		RecognizerId: c.RecognizerID,
+		Recognizer: &speechpbv2.Recognizer{
+			Model:         c.Model,
+			LanguageCodes: c.LanguageCodes,
+			DefaultRecognitionConfig: &speechpbv2.RecognitionConfig{
+				DecodingConfig: &speechpbv2.RecognitionConfig_AutoDecodingConfig{
+					AutoDecodingConfig: &speechpbv2.AutoDetectDecodingConfig{},
+				},
+				Features: &speechpbv2.RecognitionFeatures{
+					EnableWordTimeOffsets: true,
+				},
+			},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "starting create recognizer")
+	}
+	if _, err := op.Wait(ctx); err != nil {
+		return errors.Wrap(err, "waiting on create recognizer")
+	}
+
+	return nil
+}
+
+// TranscribeGSURI transcribes a recording already stored at a `gs://` uri
+// using Speech v2's `BatchRecognize`, auto-creating the configured
+// recognizer if needed. Recognition runs with per-channel separation
+// enabled, and results are translated back into this package's
+// Message/ByTime shape (including per-word timings and Channel), so callers
+// of Client can switch to ClientV2 without touching downstream code.
+func (c *ClientV2) TranscribeGSURI(ctx context.Context, uri string) ([]Message, error) {
+	if err := c.ensureRecognizer(ctx); err != nil {
+		return nil, errors.Wrap(err, "ensuring recognizer")
+	}
+
+	op, err := c.Speech.BatchRecognize(ctx, &speechpbv2.BatchRecognizeRequest{
+		Recognizer: c.recognizerName(),
+		Config: &speechpbv2.RecognitionConfig{
+			DecodingConfig: &speechpbv2.RecognitionConfig_AutoDecodingConfig{
+				AutoDecodingConfig: &speechpbv2.AutoDetectDecodingConfig{},
+			},
+			Features: &speechpbv2.RecognitionFeatures{
+				EnableWordTimeOffsets: true,
+				MultiChannelMode:      speechpbv2.RecognitionFeatures_SEPARATE_RECOGNITION_PER_CHANNEL,
+			},
+		},
+		Files: []*speechpbv2.BatchRecognizeFileMetadata{
+			{AudioSource: &speechpbv2.BatchRecognizeFileMetadata_Uri{Uri: uri}},
+		},
+		RecognitionOutputConfig: &speechpbv2.RecognitionOutputConfig{
+			Output: &speechpbv2.RecognitionOutputConfig_InlineResponseConfig{
+				InlineResponseConfig: &speechpbv2.InlineOutputConfig{},
+			},
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "starting batch recognize")
+	}
+
+	resp, err := op.Wait(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "waiting on batch recognize")
+	}
+
+	fileResult, ok := resp.Results[uri]
+	if !ok {
+		return nil, errors.Errorf("no results returned for %s", uri)
+	}
+	if fileResult.Error != nil {
+		return nil, errors.Errorf("batch recognize: %s", fileResult.Error.Message)
+	}
+
+	// ChannelTag is 1-indexed (1 == left, 2 == right), matching
+	// TranscribeURL's split path, which assigns Channel=true to the left
+	// channel.
+	var msgs []Message
+	for _, result := range fileResult.GetInlineResult().GetTranscript().GetResults() {
+		if len(result.Alternatives) == 0 || len(result.Alternatives[0].Words) == 0 {
+			continue
+		}
+		alt0 := result.Alternatives[0]
+		words := wordsFromAlternativeV2(alt0)
+
+		msgs = append(msgs, Message{
+			Channel:      result.ChannelTag == 1,
+			Offset:       words[0].Start,
+			EndOffset:    words[len(words)-1].End,
+			Text:         alt0.Transcript,
+			Confidence:   alt0.Confidence,
+			Words:        words,
+			LanguageCode: result.LanguageCode,
+			IsFinal:      true,
+		})
+	}
+
+	return msgs, nil
+}
+
+// wordsFromAlternativeV2 converts a v2 alternative's word-level results into
+// the package's own Word type. SpeakerTag is left unset: v2 attributes
+// speakers via a string SpeakerLabel, which doesn't fit this package's
+// v1-derived int32 SpeakerTag.
+func wordsFromAlternativeV2(alt *speechpbv2.SpeechRecognitionAlternative) []Word {
+	words := make([]Word, len(alt.Words))
+	for i, w := range alt.Words {
+		words[i] = Word{
+			Text:       w.Word,
+			Start:      w.StartOffset.AsDuration(),
+			End:        w.EndOffset.AsDuration(),
+			Confidence: w.Confidence,
+		}
+	}
+	return words
+}